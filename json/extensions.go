@@ -0,0 +1,35 @@
+package json
+
+// Extensions is a bitmask of relaxed, JSON5-style syntax extensions that a
+// Reader can be told to tolerate on top of strict RFC 8259 JSON.
+type Extensions uint
+
+const (
+	// AllowComments accepts "// ..." and "/* ... */" comments, treating
+	// them like whitespace.
+	AllowComments Extensions = 1 << iota
+	// AllowTrailingCommas tolerates a trailing "," before a closing "}" or "]".
+	AllowTrailingCommas
+	// AllowSingleQuotes accepts '...' strings in addition to "...".
+	AllowSingleQuotes
+	// AllowUnquotedKeys accepts bare identifiers ([A-Za-z_$][A-Za-z0-9_$]*)
+	// as object field names.
+	AllowUnquotedKeys
+	// AllowHexNumbers accepts 0x/0X-prefixed integer literals.
+	AllowHexNumbers
+	// AllowNaNInf accepts the NaN, Infinity and -Infinity number literals.
+	AllowNaNInf
+	// AllowMultilineStrings accepts raw, unescaped newlines inside strings,
+	// and "\<newline>" line continuations that are dropped from the value.
+	AllowMultilineStrings
+)
+
+// AllowExtensions relaxes the Reader's grammar to additionally accept the
+// given Extensions, e.g. r.AllowExtensions(json.AllowComments | json.AllowTrailingCommas).
+func (j *Reader) AllowExtensions(ext Extensions) {
+	j.extensions |= ext
+}
+
+func (j *Reader) hasExtension(ext Extensions) bool {
+	return j.extensions&ext != 0
+}
@@ -0,0 +1,115 @@
+package json
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestReadStringLineContinuation(t *testing.T) {
+	r := NewReader(strings.NewReader("\"line1\\\nline2\""))
+	r.AllowExtensions(AllowMultilineStrings)
+
+	v, err := r.ReadString()
+
+	if err != nil {
+		t.Fatalf("ReadString: %v", err)
+	}
+
+	if v != "line1line2" {
+		t.Fatalf("ReadString: got %q, want %q", v, "line1line2")
+	}
+}
+
+func TestReadIntHex(t *testing.T) {
+	r := NewReader(strings.NewReader("0x2A"))
+	r.AllowExtensions(AllowHexNumbers)
+
+	v, err := r.ReadInt()
+
+	if err != nil {
+		t.Fatalf("ReadInt: %v", err)
+	}
+
+	if v != 42 {
+		t.Fatalf("ReadInt: got %v, want 42", v)
+	}
+}
+
+func TestReadRelaxedObject(t *testing.T) {
+	src := `{
+		// a comment
+		$foo: 'bar',
+		baz: 1,
+	}`
+
+	r := NewReader(strings.NewReader(src))
+	r.AllowExtensions(AllowComments | AllowTrailingCommas | AllowSingleQuotes | AllowUnquotedKeys)
+
+	if err := r.ReadStartObject(); err != nil {
+		t.Fatalf("ReadStartObject: %v", err)
+	}
+
+	got := map[string]int{}
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			t.Fatalf("ReadFieldName: %v", err)
+		}
+
+		switch name {
+		case "$foo":
+			v, err := r.ReadString()
+
+			if err != nil {
+				t.Fatalf("ReadString: %v", err)
+			}
+
+			got[name] = len(v)
+		case "baz":
+			v, err := r.ReadInt()
+
+			if err != nil {
+				t.Fatalf("ReadInt: %v", err)
+			}
+
+			got[name] = v
+		}
+
+		r.ReadEndField()
+	}
+
+	if got["$foo"] != 3 || got["baz"] != 1 {
+		t.Fatalf("ReadRelaxedObject: got %v", got)
+	}
+}
+
+func TestReadFloatNaNInf(t *testing.T) {
+	r := NewReader(strings.NewReader("NaN"))
+	r.AllowExtensions(AllowNaNInf)
+
+	v, err := r.ReadFloat()
+
+	if err != nil {
+		t.Fatalf("ReadFloat(NaN): %v", err)
+	}
+
+	if !math.IsNaN(v) {
+		t.Fatalf("ReadFloat(NaN): got %v, want NaN", v)
+	}
+
+	r = NewReader(strings.NewReader("-Infinity"))
+	r.AllowExtensions(AllowNaNInf)
+
+	v, err = r.ReadFloat()
+
+	if err != nil {
+		t.Fatalf("ReadFloat(-Infinity): %v", err)
+	}
+
+	if !math.IsInf(v, -1) {
+		t.Fatalf("ReadFloat(-Infinity): got %v, want -Inf", v)
+	}
+}
@@ -0,0 +1,570 @@
+// Package jsonpath evaluates JSONPath expressions against a json.Reader
+// stream without materializing the whole document. A compiled Query is
+// driven directly from the reader's tokens: it tracks, at every depth, which
+// path segments are still looking for a match, and only buffers the bytes of
+// a value when that value (or one of its ancestors) actually matches, or when
+// it is an array element that a filter predicate has to test.
+package jsonpath
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/YiCodes/go-encoding/json"
+)
+
+// RawMessage holds the JSON text of a matched value. It is re-encoded from
+// the decoded tokens rather than copied from the input: strings and keys are
+// requoted with Go quoting and numbers are reformatted through float64.
+type RawMessage []byte
+
+// Query is a compiled JSONPath expression ready to be evaluated against a
+// json.Reader.
+type Query struct {
+	segments []segment
+}
+
+type segKind int
+
+const (
+	segKey segKind = iota
+	segWildcard
+	segRecursive
+	segIndex
+	segFilter
+)
+
+// segment is one step of a compiled path, e.g. the "book" in "$.store.book".
+// A segRecursive segment never appears alone: it is always immediately
+// followed by the segment that should match at any depth below it.
+type segment struct {
+	kind   segKind
+	key    string
+	index  int
+	filter *filterExpr
+}
+
+type filterExpr struct {
+	field   string
+	op      string
+	literal string
+}
+
+// Compile parses a JSONPath expression such as "$.store.book[*].author",
+// "$..price" or "$.items[?(@.qty>10)].id" into a Query.
+func Compile(expr string) (*Query, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("jsonpath: expression must start with $: %q", expr)
+	}
+
+	p := &parser{src: expr, pos: 1}
+	var segs []segment
+
+	for p.pos < len(p.src) {
+		next, err := p.next()
+
+		if err != nil {
+			return nil, err
+		}
+
+		segs = append(segs, next...)
+	}
+
+	return &Query{segments: segs}, nil
+}
+
+// Eval drives r and invokes yield once for every value matching the query,
+// along with a breadcrumb path such as "$.store.book[0].author". Returning
+// an error from yield aborts evaluation and is returned from Eval unchanged.
+func (q *Query) Eval(r *json.Reader, yield func(path string, value RawMessage) error) error {
+	_, err := evalValue(r, [][]segment{q.segments}, false, "$", yield)
+
+	return err
+}
+
+// partition splits states into those that already match at this node
+// (empty segment slice) and those still pending further descent.
+func partition(states [][]segment) (complete bool, pending [][]segment) {
+	for _, st := range states {
+		if len(st) == 0 {
+			complete = true
+		} else {
+			pending = append(pending, st)
+		}
+	}
+
+	return
+}
+
+// splitRecursive expands any segRecursive head into (a) the segment that
+// follows it, tested against this node's own children, and (b) the
+// unchanged state, propagated unconditionally to every child so the search
+// keeps going at every depth.
+func splitRecursive(pending [][]segment) (heads [][]segment, always [][]segment) {
+	for _, st := range pending {
+		if st[0].kind == segRecursive {
+			always = append(always, st)
+
+			if len(st) > 1 {
+				heads = append(heads, st[1:])
+			}
+		} else {
+			heads = append(heads, st)
+		}
+	}
+
+	return
+}
+
+func splitFilters(heads [][]segment) (filters [][]segment, rest [][]segment) {
+	for _, st := range heads {
+		if st[0].kind == segFilter {
+			filters = append(filters, st)
+		} else {
+			rest = append(rest, st)
+		}
+	}
+
+	return
+}
+
+func matchKey(heads [][]segment, name string) [][]segment {
+	var out [][]segment
+
+	for _, st := range heads {
+		switch st[0].kind {
+		case segKey:
+			if st[0].key == name {
+				out = append(out, st[1:])
+			}
+		case segWildcard:
+			out = append(out, st[1:])
+		}
+	}
+
+	return out
+}
+
+func matchIndex(heads [][]segment, index int) [][]segment {
+	var out [][]segment
+
+	for _, st := range heads {
+		switch st[0].kind {
+		case segIndex:
+			if st[0].index == index {
+				out = append(out, st[1:])
+			}
+		case segWildcard:
+			out = append(out, st[1:])
+		}
+	}
+
+	return out
+}
+
+// evalValue evaluates the active states against the next value in r. When
+// needRaw is true (because this value or an ancestor matched, or a filter
+// has to test it), it returns
+// the value's raw JSON bytes; otherwise it returns nil and merely walks far
+// enough to keep the stream in sync, skipping dead branches cheaply.
+func evalValue(r *json.Reader, states [][]segment, needRaw bool, path string, yield func(string, RawMessage) error) ([]byte, error) {
+	complete, pending := partition(states)
+
+	if complete {
+		needRaw = true
+	}
+
+	if !needRaw && len(pending) == 0 {
+		return nil, r.SkipValue()
+	}
+
+	var raw []byte
+	var err error
+
+	switch r.PeekKind() {
+	case json.KindObject:
+		raw, err = evalObject(r, pending, needRaw, path, yield)
+	case json.KindArray:
+		raw, err = evalArray(r, pending, needRaw, path, yield)
+	default:
+		raw, err = readScalar(r, needRaw)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if complete {
+		if err := yield(path, RawMessage(raw)); err != nil {
+			return nil, err
+		}
+	}
+
+	return raw, nil
+}
+
+func evalObject(r *json.Reader, pending [][]segment, needRaw bool, path string, yield func(string, RawMessage) error) ([]byte, error) {
+	if err := r.ReadStartObject(); err != nil {
+		return nil, err
+	}
+
+	heads, always := splitRecursive(pending)
+
+	var buf bytes.Buffer
+
+	if needRaw {
+		buf.WriteByte('{')
+	}
+
+	first := true
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		childStates := append(matchKey(heads, name), always...)
+		childPath := path + "." + name
+
+		childRaw, err := evalValue(r, childStates, needRaw, childPath, yield)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if needRaw {
+			if !first {
+				buf.WriteByte(',')
+			}
+
+			first = false
+			fmt.Fprintf(&buf, "%q:%s", name, childRaw)
+		}
+
+		r.ReadEndField()
+	}
+
+	if !needRaw {
+		return nil, nil
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func evalArray(r *json.Reader, pending [][]segment, needRaw bool, path string, yield func(string, RawMessage) error) ([]byte, error) {
+	if err := r.ReadStartArray(); err != nil {
+		return nil, err
+	}
+
+	heads, always := splitRecursive(pending)
+	filters, otherHeads := splitFilters(heads)
+	forceRaw := needRaw || len(filters) > 0
+
+	var buf bytes.Buffer
+
+	if needRaw {
+		buf.WriteByte('[')
+	}
+
+	first := true
+	index := 0
+
+	for !r.TryReadEndArray() {
+		childStates := append(matchIndex(otherHeads, index), always...)
+		childPath := fmt.Sprintf("%s[%d]", path, index)
+
+		childRaw, err := evalValue(r, childStates, forceRaw, childPath, yield)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if len(filters) > 0 {
+			var continuing [][]segment
+
+			for _, st := range filters {
+				if st[0].filter.matches(childRaw) {
+					continuing = append(continuing, st[1:])
+				}
+			}
+
+			if len(continuing) > 0 {
+				sub := json.NewReader(bytes.NewReader(childRaw))
+
+				if _, err := evalValue(sub, continuing, needRaw, childPath, yield); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if needRaw {
+			if !first {
+				buf.WriteByte(',')
+			}
+
+			first = false
+			buf.Write(childRaw)
+		}
+
+		r.ReadEndField()
+		index++
+	}
+
+	if !needRaw {
+		return nil, nil
+	}
+
+	buf.WriteByte(']')
+
+	return buf.Bytes(), nil
+}
+
+func readScalar(r *json.Reader, needRaw bool) ([]byte, error) {
+	switch r.PeekKind() {
+	case json.KindString:
+		s, err := r.ReadString()
+
+		if err != nil || !needRaw {
+			return nil, err
+		}
+
+		return []byte(strconv.Quote(s)), nil
+	case json.KindNumber:
+		f, err := r.ReadFloat()
+
+		if err != nil || !needRaw {
+			return nil, err
+		}
+
+		return []byte(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case json.KindBool:
+		b, err := r.ReadBool()
+
+		if err != nil || !needRaw {
+			return nil, err
+		}
+
+		return []byte(strconv.FormatBool(b)), nil
+	case json.KindNull:
+		r.TryReadNull()
+
+		if !needRaw {
+			return nil, nil
+		}
+
+		return []byte("null"), nil
+	default:
+		return nil, fmt.Errorf("jsonpath: unexpected token reading scalar value")
+	}
+}
+
+// matches evaluates the filter's "@.field op literal" predicate against the
+// raw JSON object bytes of a candidate array element.
+func (f *filterExpr) matches(raw []byte) bool {
+	rr := json.NewReader(bytes.NewReader(raw))
+
+	if rr.PeekKind() != json.KindObject {
+		return false
+	}
+
+	if err := rr.ReadStartObject(); err != nil {
+		return false
+	}
+
+	for !rr.TryReadEndObject() {
+		name, err := rr.ReadFieldName()
+
+		if err != nil {
+			return false
+		}
+
+		if name != f.field {
+			rr.SkipValue()
+			rr.ReadEndField()
+			continue
+		}
+
+		matched := false
+
+		switch rr.PeekKind() {
+		case json.KindNumber:
+			v, err := rr.ReadFloat()
+
+			if err == nil {
+				if lit, err := strconv.ParseFloat(f.literal, 64); err == nil {
+					matched = compareNumber(v, f.op, lit)
+				}
+			}
+		case json.KindString:
+			v, err := rr.ReadString()
+
+			if err == nil {
+				matched = compareString(v, f.op, f.literal)
+			}
+		default:
+			rr.SkipValue()
+		}
+
+		rr.ReadEndField()
+
+		return matched
+	}
+
+	return false
+}
+
+func compareNumber(v float64, op string, lit float64) bool {
+	switch op {
+	case "==":
+		return v == lit
+	case "!=":
+		return v != lit
+	case ">":
+		return v > lit
+	case ">=":
+		return v >= lit
+	case "<":
+		return v < lit
+	case "<=":
+		return v <= lit
+	}
+
+	return false
+}
+
+func compareString(v, op, lit string) bool {
+	switch op {
+	case "==":
+		return v == lit
+	case "!=":
+		return v != lit
+	}
+
+	return false
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+// next parses one step of the path, starting at a '.' or '['. A recursive
+// descent ".." has no separating '.' before the segment it modifies (e.g.
+// "$..price", "$..*", "$..book[*]"), so it is special-cased to also consume
+// that segment here and return both of them together.
+func (p *parser) next() ([]segment, error) {
+	switch p.src[p.pos] {
+	case '.':
+		p.pos++
+
+		if p.pos < len(p.src) && p.src[p.pos] == '.' {
+			p.pos++
+
+			seg, err := p.parseRecursiveTarget()
+
+			if err != nil {
+				return nil, err
+			}
+
+			return []segment{{kind: segRecursive}, seg}, nil
+		}
+
+		seg, err := p.parseDotName()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []segment{seg}, nil
+	case '[':
+		seg, err := p.parseBracket()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []segment{seg}, nil
+	default:
+		return nil, fmt.Errorf("jsonpath: unexpected character %q at %d", p.src[p.pos], p.pos)
+	}
+}
+
+// parseRecursiveTarget parses the key, wildcard or bracket segment that a
+// ".." binds to, which (unlike every other segment) isn't preceded by '.'.
+func (p *parser) parseRecursiveTarget() (segment, error) {
+	if p.pos < len(p.src) && p.src[p.pos] == '[' {
+		return p.parseBracket()
+	}
+
+	return p.parseDotName()
+}
+
+func (p *parser) parseDotName() (segment, error) {
+	start := p.pos
+
+	for p.pos < len(p.src) && p.src[p.pos] != '.' && p.src[p.pos] != '[' {
+		p.pos++
+	}
+
+	name := p.src[start:p.pos]
+
+	if name == "*" {
+		return segment{kind: segWildcard}, nil
+	}
+
+	if name == "" {
+		return segment{}, fmt.Errorf("jsonpath: empty name at %d", start)
+	}
+
+	return segment{kind: segKey, key: name}, nil
+}
+
+func (p *parser) parseBracket() (segment, error) {
+	end := strings.IndexByte(p.src[p.pos:], ']')
+
+	if end < 0 {
+		return segment{}, fmt.Errorf("jsonpath: unterminated [ at %d", p.pos)
+	}
+
+	inner := p.src[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	switch {
+	case inner == "*":
+		return segment{kind: segWildcard}, nil
+	case strings.HasPrefix(inner, "?("):
+		return parseFilter(inner)
+	case strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'"):
+		return segment{kind: segKey, key: strings.Trim(inner, "'")}, nil
+	default:
+		idx, err := strconv.Atoi(inner)
+
+		if err != nil {
+			return segment{}, fmt.Errorf("jsonpath: invalid index %q", inner)
+		}
+
+		return segment{kind: segIndex, index: idx}, nil
+	}
+}
+
+func parseFilter(inner string) (segment, error) {
+	body := strings.TrimSuffix(strings.TrimPrefix(inner, "?("), ")")
+	body = strings.TrimPrefix(strings.TrimSpace(body), "@.")
+
+	for _, op := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		if i := strings.Index(body, op); i >= 0 {
+			field := strings.TrimSpace(body[:i])
+			literal := strings.Trim(strings.TrimSpace(body[i+len(op):]), `"'`)
+
+			return segment{kind: segFilter, filter: &filterExpr{field: field, op: op, literal: literal}}, nil
+		}
+	}
+
+	return segment{}, fmt.Errorf("jsonpath: unsupported filter expression %q", inner)
+}
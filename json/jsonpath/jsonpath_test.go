@@ -0,0 +1,106 @@
+package jsonpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/YiCodes/go-encoding/json"
+)
+
+func TestQueryEvalWildcardAndFilter(t *testing.T) {
+	doc := `{"store":{"book":[
+		{"title":"A","price":10},
+		{"title":"B","price":25}
+	]}}`
+
+	q, err := Compile("$.store.book[*].title")
+
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	var titles []string
+
+	err = q.Eval(json.NewReader(strings.NewReader(doc)), func(path string, value RawMessage) error {
+		titles = append(titles, string(value))
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+
+	if len(titles) != 2 || titles[0] != `"A"` || titles[1] != `"B"` {
+		t.Fatalf("Eval wildcard: got %v", titles)
+	}
+
+	q, err = Compile(`$.store.book[?(@.price>20)].title`)
+
+	if err != nil {
+		t.Fatalf("Compile filter: %v", err)
+	}
+
+	titles = nil
+
+	err = q.Eval(json.NewReader(strings.NewReader(doc)), func(path string, value RawMessage) error {
+		titles = append(titles, string(value))
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Eval filter: %v", err)
+	}
+
+	if len(titles) != 1 || titles[0] != `"B"` {
+		t.Fatalf("Eval filter: got %v", titles)
+	}
+}
+
+func TestQueryEvalRecursiveDescent(t *testing.T) {
+	doc := `{"store":{"book":[
+		{"title":"A","price":10},
+		{"title":"B","price":25}
+	]}}`
+
+	q, err := Compile("$..price")
+
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+
+	var prices []string
+
+	err = q.Eval(json.NewReader(strings.NewReader(doc)), func(path string, value RawMessage) error {
+		prices = append(prices, string(value))
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Eval: %v", err)
+	}
+
+	if len(prices) != 2 || prices[0] != "10" || prices[1] != "25" {
+		t.Fatalf("Eval recursive: got %v", prices)
+	}
+
+	q, err = Compile("$..book[*].title")
+
+	if err != nil {
+		t.Fatalf("Compile nested: %v", err)
+	}
+
+	var titles []string
+
+	err = q.Eval(json.NewReader(strings.NewReader(doc)), func(path string, value RawMessage) error {
+		titles = append(titles, string(value))
+		return nil
+	})
+
+	if err != nil {
+		t.Fatalf("Eval nested: %v", err)
+	}
+
+	if len(titles) != 2 || titles[0] != `"A"` || titles[1] != `"B"` {
+		t.Fatalf("Eval nested recursive: got %v", titles)
+	}
+}
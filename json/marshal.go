@@ -0,0 +1,636 @@
+package json
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var numberType = reflect.TypeOf(Number(""))
+var rawMessageType = reflect.TypeOf(RawMessage(nil))
+
+// Marshaler is implemented by types that can render their own JSON encoding.
+type Marshaler interface {
+	MarshalJSON() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that can parse their own JSON encoding.
+type Unmarshaler interface {
+	UnmarshalJSON([]byte) error
+}
+
+// Marshal returns the JSON encoding of v, driving a Writer via reflection.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if err := NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal parses JSON-encoded data and stores the result in the value
+// pointed to by v, driving a Reader via reflection.
+func Unmarshal(data []byte, v any) error {
+	return NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Encoder writes a stream of JSON values, one per Encode call, on top of a
+// low-level Writer.
+type Encoder struct {
+	w *Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: NewWriter(w)}
+}
+
+func (e *Encoder) Encode(v any) error {
+	if err := encodeValue(e.w, reflect.ValueOf(v)); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// Decoder reads a stream of JSON values, one per Decode call, on top of a
+// low-level Reader.
+type Decoder struct {
+	r *Reader
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: NewReader(r)}
+}
+
+// UseNumber makes Decode produce a Number instead of a float64 for JSON
+// numbers decoded into interface{} values.
+func (d *Decoder) UseNumber() {
+	d.r.UseNumber()
+}
+
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("json: Decode requires a non-nil pointer, got %T", v)
+	}
+
+	return decodeValue(d.r, rv.Elem())
+}
+
+func encodeValue(w *Writer, v reflect.Value) error {
+	if !v.IsValid() {
+		return w.WriteNull()
+	}
+
+	if v.CanInterface() {
+		if m, ok := v.Interface().(Marshaler); ok {
+			raw, err := m.MarshalJSON()
+
+			if err != nil {
+				return err
+			}
+
+			return w.WriteRaw(raw)
+		}
+	}
+
+	if v.Type() == numberType {
+		return w.WriteRaw([]byte(v.String()))
+	}
+
+	if v.Type() == rawMessageType {
+		if v.IsNil() {
+			return w.WriteNull()
+		}
+
+		return w.WriteRaw(v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return w.WriteNull()
+		}
+
+		return encodeValue(w, v.Elem())
+	case reflect.Struct:
+		return encodeStruct(w, v)
+	case reflect.Map:
+		return encodeMap(w, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			return w.WriteNull()
+		}
+
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return w.WriteString(string(v.Bytes()))
+		}
+
+		return encodeSequence(w, v)
+	case reflect.Array:
+		return encodeSequence(w, v)
+	case reflect.String:
+		return w.WriteString(v.String())
+	case reflect.Bool:
+		return w.WriteBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return w.WriteInt64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return w.WriteUint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return w.WriteFloat(v.Float())
+	default:
+		return fmt.Errorf("json: unsupported type %s", v.Type())
+	}
+}
+
+func encodeSequence(w *Writer, v reflect.Value) error {
+	if err := w.WriteStartArray(); err != nil {
+		return err
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := encodeValue(w, v.Index(i)); err != nil {
+			return err
+		}
+	}
+
+	return w.WriteEndArray()
+}
+
+func encodeMap(w *Writer, v reflect.Value) error {
+	if v.IsNil() {
+		return w.WriteNull()
+	}
+
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("json: unsupported map key type %s", v.Type().Key())
+	}
+
+	if err := w.WriteStartObject(); err != nil {
+		return err
+	}
+
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	for _, k := range keys {
+		if err := w.WriteStartField(k.String()); err != nil {
+			return err
+		}
+
+		if err := encodeValue(w, v.MapIndex(k)); err != nil {
+			return err
+		}
+	}
+
+	return w.WriteEndObject()
+}
+
+func encodeStruct(w *Writer, v reflect.Value) error {
+	if err := w.WriteStartObject(); err != nil {
+		return err
+	}
+
+	for _, f := range structFields(v.Type()) {
+		fv := v.FieldByIndex(f.index)
+
+		if f.omitempty && isEmptyValue(fv) {
+			continue
+		}
+
+		if err := w.WriteStartField(f.name); err != nil {
+			return err
+		}
+
+		if err := encodeValue(w, fv); err != nil {
+			return err
+		}
+	}
+
+	return w.WriteEndObject()
+}
+
+func decodeValue(r *Reader, v reflect.Value) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+
+		return decodeValue(r, v.Elem())
+	}
+
+	if v.CanAddr() && v.Addr().CanInterface() {
+		if u, ok := v.Addr().Interface().(Unmarshaler); ok {
+			raw, err := r.ReadRawValue()
+
+			if err != nil {
+				return err
+			}
+
+			return u.UnmarshalJSON(raw)
+		}
+	}
+
+	if v.Type() == rawMessageType {
+		raw, err := r.ReadRawValue()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetBytes(raw)
+
+		return nil
+	}
+
+	if r.PeekKind() == KindNull {
+		r.TryReadNull()
+		v.Set(reflect.Zero(v.Type()))
+
+		return nil
+	}
+
+	if v.Type() == numberType {
+		n, err := r.ReadNumber()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetString(string(n))
+
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return decodeStruct(r, v)
+	case reflect.Map:
+		return decodeMap(r, v)
+	case reflect.Slice, reflect.Array:
+		return decodeSequence(r, v)
+	case reflect.String:
+		s, err := r.ReadString()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetString(s)
+
+		return nil
+	case reflect.Bool:
+		b, err := r.ReadBool()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetBool(b)
+
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := r.ReadInt64()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetInt(n)
+
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := r.ReadInt64()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetUint(uint64(n))
+
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := r.ReadFloat()
+
+		if err != nil {
+			return err
+		}
+
+		v.SetFloat(f)
+
+		return nil
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("json: unsupported interface type %s", v.Type())
+		}
+
+		val, err := decodeAny(r)
+
+		if err != nil {
+			return err
+		}
+
+		if val != nil {
+			v.Set(reflect.ValueOf(val))
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("json: unsupported type %s", v.Type())
+	}
+}
+
+func decodeStruct(r *Reader, v reflect.Value) error {
+	if err := r.ReadStartObject(); err != nil {
+		return err
+	}
+
+	fields := structFields(v.Type())
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			return err
+		}
+
+		matched := false
+
+		for _, f := range fields {
+			if f.name == name {
+				if err := decodeValue(r, v.FieldByIndex(f.index)); err != nil {
+					return err
+				}
+
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			if err := skipAnyValue(r); err != nil {
+				return err
+			}
+		}
+
+		r.ReadEndField()
+	}
+
+	return nil
+}
+
+func decodeMap(r *Reader, v reflect.Value) error {
+	if v.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("json: unsupported map key type %s", v.Type().Key())
+	}
+
+	if err := r.ReadStartObject(); err != nil {
+		return err
+	}
+
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	elemType := v.Type().Elem()
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			return err
+		}
+
+		elem := reflect.New(elemType).Elem()
+
+		if err := decodeValue(r, elem); err != nil {
+			return err
+		}
+
+		v.SetMapIndex(reflect.ValueOf(name).Convert(v.Type().Key()), elem)
+
+		r.ReadEndField()
+	}
+
+	return nil
+}
+
+func decodeSequence(r *Reader, v reflect.Value) error {
+	if err := r.ReadStartArray(); err != nil {
+		return err
+	}
+
+	if v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	}
+
+	elemType := v.Type().Elem()
+	i := 0
+
+	for !r.TryReadEndArray() {
+		if v.Kind() == reflect.Array && i >= v.Len() {
+			if err := skipAnyValue(r); err != nil {
+				return err
+			}
+		} else {
+			elem := reflect.New(elemType).Elem()
+
+			if err := decodeValue(r, elem); err != nil {
+				return err
+			}
+
+			if v.Kind() == reflect.Slice {
+				v.Set(reflect.Append(v, elem))
+			} else {
+				v.Index(i).Set(elem)
+			}
+		}
+
+		r.ReadEndField()
+		i++
+	}
+
+	return nil
+}
+
+// decodeAny decodes the next value into its natural Go representation
+// (map[string]any, []any, string, float64, bool or nil), mirroring
+// encoding/json's behavior when unmarshaling into an interface{}.
+func decodeAny(r *Reader) (any, error) {
+	switch r.PeekKind() {
+	case KindNull:
+		r.TryReadNull()
+		return nil, nil
+	case KindBool:
+		return r.ReadBool()
+	case KindNumber:
+		if r.useNumber {
+			return r.ReadNumber()
+		}
+
+		return r.ReadFloat()
+	case KindString:
+		return r.ReadString()
+	case KindArray:
+		if err := r.ReadStartArray(); err != nil {
+			return nil, err
+		}
+
+		var out []any
+
+		for !r.TryReadEndArray() {
+			v, err := decodeAny(r)
+
+			if err != nil {
+				return nil, err
+			}
+
+			out = append(out, v)
+			r.ReadEndField()
+		}
+
+		return out, nil
+	case KindObject:
+		if err := r.ReadStartObject(); err != nil {
+			return nil, err
+		}
+
+		out := make(map[string]any)
+
+		for !r.TryReadEndObject() {
+			name, err := r.ReadFieldName()
+
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := decodeAny(r)
+
+			if err != nil {
+				return nil, err
+			}
+
+			out[name] = v
+			r.ReadEndField()
+		}
+
+		return out, nil
+	default:
+		return nil, fmt.Errorf("json: unexpected token decoding into interface{}")
+	}
+}
+
+func skipAnyValue(r *Reader) error {
+	_, err := decodeAny(r)
+
+	return err
+}
+
+type fieldInfo struct {
+	name      string
+	index     []int
+	omitempty bool
+}
+
+var structFieldCache sync.Map // map[reflect.Type][]fieldInfo
+
+// structFields returns t's JSON-relevant fields in declaration order,
+// honoring `json:"name,omitempty"` tags and flattening the fields of untagged
+// anonymous struct fields into the parent. Name conflicts between promoted
+// fields are not resolved: every such field is kept, in declaration order.
+func structFields(t reflect.Type) []fieldInfo {
+	if cached, ok := structFieldCache.Load(t); ok {
+		return cached.([]fieldInfo)
+	}
+
+	fields := collectFields(t, nil)
+	structFieldCache.Store(t, fields)
+
+	return fields
+}
+
+func collectFields(t reflect.Type, index []int) []fieldInfo {
+	var fields []fieldInfo
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+
+		tag := sf.Tag.Get("json")
+
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := parseTag(tag)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, collectFields(ft, fieldIndex)...)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+
+		fields = append(fields, fieldInfo{name: name, index: fieldIndex, omitempty: hasOption(opts, "omitempty")})
+	}
+
+	return fields
+}
+
+func parseTag(tag string) (name string, opts []string) {
+	parts := strings.Split(tag, ",")
+
+	return parts[0], parts[1:]
+}
+
+func hasOption(opts []string, name string) bool {
+	for _, o := range opts {
+		if o == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+
+	return false
+}
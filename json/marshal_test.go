@@ -0,0 +1,58 @@
+package json
+
+import "testing"
+
+func TestMarshalRawMessage(t *testing.T) {
+	type wrapper struct {
+		Extra RawMessage `json:"extra"`
+	}
+
+	in := wrapper{Extra: RawMessage(`{"x":1}`)}
+
+	data, err := Marshal(in)
+
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	if string(data) != `{"extra":{"x":1}}` {
+		t.Fatalf("Marshal: got %s, want {\"extra\":{\"x\":1}}", data)
+	}
+
+	var out wrapper
+
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if string(out.Extra) != `{"x":1}` {
+		t.Fatalf("Unmarshal: got %s, want {\"x\":1}", out.Extra)
+	}
+}
+
+func TestUnmarshalTrailingBoolField(t *testing.T) {
+	type wrapper struct {
+		B string `json:"b"`
+		A bool   `json:"a"`
+	}
+
+	var out wrapper
+
+	if err := Unmarshal([]byte(`{"b":"hello","a":true}`), &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if out.B != "hello" || out.A != true {
+		t.Fatalf("Unmarshal: got %+v", out)
+	}
+
+	var bools []bool
+
+	if err := Unmarshal([]byte(`[true,false,true]`), &bools); err != nil {
+		t.Fatalf("Unmarshal slice: %v", err)
+	}
+
+	if len(bools) != 3 || bools[0] != true || bools[1] != false || bools[2] != true {
+		t.Fatalf("Unmarshal slice: got %v", bools)
+	}
+}
@@ -0,0 +1,59 @@
+package json
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// Number is a JSON number stored as its exact source text, so callers can
+// defer choosing between an integer and floating-point representation (or
+// avoid float64's precision loss entirely) until they actually need the
+// value. It mirrors encoding/json's Number.
+type Number string
+
+// Int64 parses the number as a base-10 integer.
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 parses the number as a float64.
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// BigFloat parses the number as an arbitrary-precision big.Float, for
+// callers that need more precision than float64 offers.
+func (n Number) BigFloat() (*big.Float, error) {
+	f, _, err := big.ParseFloat(string(n), 10, big.MaxPrec, big.ToNearestEven)
+
+	if err != nil {
+		return nil, fmt.Errorf("json: invalid number literal %q: %w", string(n), err)
+	}
+
+	return f, nil
+}
+
+func (n Number) String() string {
+	return string(n)
+}
+
+// UseNumber makes subsequent decoding into interface{} values (through
+// decodeAny, e.g. via Decoder.Decode after Decoder.UseNumber) produce a Number instead of
+// a float64 for JSON numbers, so large integers and exact decimals survive
+// round-tripping.
+func (j *Reader) UseNumber() {
+	j.useNumber = true
+}
+
+// ReadNumber reads the next JSON number as a Number, preserving its exact
+// source text instead of rounding it through float64.
+func (j *Reader) ReadNumber() (Number, error) {
+	t, err := j.eat(kindNumber)
+
+	if err != nil {
+		return "", err
+	}
+
+	return Number(t.String()), nil
+}
@@ -0,0 +1,48 @@
+package json
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNumberInt64(t *testing.T) {
+	v, err := Number("42").Int64()
+
+	if err != nil {
+		t.Fatalf("Int64: %v", err)
+	}
+
+	if v != 42 {
+		t.Fatalf("Int64: got %v, want 42", v)
+	}
+}
+
+func TestNumberFloat64(t *testing.T) {
+	v, err := Number("1.5").Float64()
+
+	if err != nil {
+		t.Fatalf("Float64: %v", err)
+	}
+
+	if v != 1.5 {
+		t.Fatalf("Float64: got %v, want 1.5", v)
+	}
+}
+
+func TestNumberBigFloat(t *testing.T) {
+	f, err := Number("123456789012345678901234567890").BigFloat()
+
+	if err != nil {
+		t.Fatalf("BigFloat: %v", err)
+	}
+
+	want, _, err := big.ParseFloat("123456789012345678901234567890", 10, big.MaxPrec, big.ToNearestEven)
+
+	if err != nil {
+		t.Fatalf("big.ParseFloat: %v", err)
+	}
+
+	if f.Cmp(want) != 0 {
+		t.Fatalf("BigFloat: got %v, want %v", f, want)
+	}
+}
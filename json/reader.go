@@ -10,13 +10,16 @@ import (
 )
 
 type Reader struct {
-	reader      *bufio.Reader
-	peekedToken jsonToken
-	char        rune
-	charPeeked  bool
-	row         int
-	col         int
-	offset      int
+	reader       *bufio.Reader
+	peekedToken  jsonToken
+	peekedToken2 jsonToken
+	char         rune
+	charPeeked   bool
+	row          int
+	col          int
+	offset       int
+	extensions   Extensions
+	useNumber    bool
 }
 
 type position struct {
@@ -136,24 +139,38 @@ func (j *Reader) readChar() (rune, error) {
 	return j.char, nil
 }
 
+// peekChar returns the next rune without consuming it, caching it in j.char
+// so a following peekChar (before any readChar) returns the same rune
+// instead of pulling another one off the underlying reader.
 func (j *Reader) peekChar() (rune, bool) {
+	if j.charPeeked {
+		return j.char, true
+	}
+
 	r, _, err := j.reader.ReadRune()
 
-	if err == nil {
-		j.charPeeked = true
-		return r, true
+	if err != nil {
+		return r, false
 	}
 
-	return r, false
+	j.char = r
+	j.charPeeked = true
+
+	return r, true
 }
 
 func (j *Reader) nextToken() jsonToken {
 	if j.peekedToken != nil {
 		t := j.peekedToken
-		j.peekedToken = nil
+		j.peekedToken = j.peekedToken2
+		j.peekedToken2 = nil
 		return t
 	}
 
+	return j.lexToken()
+}
+
+func (j *Reader) lexToken() jsonToken {
 	for {
 		pos := j.getPos()
 		c, ok := j.peekChar()
@@ -162,12 +179,28 @@ func (j *Reader) nextToken() jsonToken {
 			return j.newBasicToken(kindEOF, "", pos)
 		}
 
-		if c >= '0' && c <= '9' {
+		if j.hasExtension(AllowComments) && c == '/' {
+			skipped, err := j.trySkipComment()
+
+			if err != nil {
+				return j.newBasicToken(kindBadToken, err.Error(), pos)
+			}
+
+			if skipped {
+				continue
+			}
+		}
+
+		if (c >= '0' && c <= '9') || c == '-' {
 			return j.readNumberToken()
 		} else if c == '"' {
 			return j.readStringToken()
+		} else if j.hasExtension(AllowSingleQuotes) && c == '\'' {
+			return j.readStringToken()
 		} else if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
 			return j.readKeywordToken()
+		} else if j.hasExtension(AllowUnquotedKeys) && (c == '_' || c == '$') {
+			return j.readKeywordToken()
 		} else if unicode.IsSpace(c) {
 			j.readChar()
 			continue
@@ -206,25 +239,48 @@ func (j *Reader) readKeywordToken() jsonToken {
 	for {
 		c, ok := j.peekChar()
 
-		if ok && ((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
-			j.readChar()
-			b.WriteRune(c)
+		if !ok {
+			break
+		}
+
+		isLetter := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isExtendedIdentChar := j.hasExtension(AllowUnquotedKeys) &&
+			(isLetter || c == '_' || c == '$' || (b.Len() > 0 && c >= '0' && c <= '9'))
+
+		if !isLetter && !isExtendedIdentChar {
+			break
 		}
 
-		break
+		j.readChar()
+		b.WriteRune(c)
 	}
 
 	value := b.String()
-	var kind tokenKind
 
-	if value == "true" {
-		kind = kindTrue
-	} else if value == "false" {
-		kind = kindFalse
-	} else if value == "null" {
-		kind = kindNull
+	switch value {
+	case "true":
+		return j.newBasicToken(kindTrue, value, pos)
+	case "false":
+		return j.newBasicToken(kindFalse, value, pos)
+	case "null":
+		return j.newBasicToken(kindNull, value, pos)
 	}
 
+	if j.hasExtension(AllowNaNInf) && (value == "NaN" || value == "Infinity") {
+		return j.newBasicToken(kindNumber, value, pos)
+	}
+
+	if j.hasExtension(AllowUnquotedKeys) && value != "" {
+		t := &jsonStringToken{}
+		t.kind = kindString
+		t.str = value
+		t.value = value
+		t.startPos = *pos
+		return t
+	}
+
+	var kind tokenKind
+
 	return j.newBasicToken(kind, value, pos)
 }
 
@@ -234,7 +290,7 @@ func (j *Reader) readStringToken() jsonToken {
 
 	pos := j.getPos()
 
-	j.readChar()
+	quote, _ := j.readChar()
 	j.col++
 
 	for {
@@ -248,11 +304,32 @@ func (j *Reader) readStringToken() jsonToken {
 
 		if escape {
 			escape = false
-		} else if c == '"' {
+			j.readChar()
+
+			// "\<newline>" is a line continuation: the backslash held back
+			// above and this newline are both dropped rather than written.
+			if (c == '\r' || c == '\n') && j.hasExtension(AllowMultilineStrings) {
+				continue
+			}
+
+			b.WriteByte('\\')
+			b.WriteRune(c)
+			continue
+		}
+
+		if c == quote {
 			break
-		} else if c == '\\' {
+		}
+
+		if c == '\\' {
+			// Hold the backslash back until we see what follows it, since a
+			// line continuation writes neither char.
 			escape = true
-		} else if c == '\r' || c == '\n' {
+			j.readChar()
+			continue
+		}
+
+		if (c == '\r' || c == '\n') && !j.hasExtension(AllowMultilineStrings) {
 			return j.newBasicToken(kindBadToken, b.String(), pos)
 		}
 
@@ -261,14 +338,69 @@ func (j *Reader) readStringToken() jsonToken {
 		b.WriteRune(c)
 	}
 
+	j.readChar()
+
 	t := &jsonStringToken{}
 	t.kind = kindString
 	t.str = b.String()
-	t.value = fmt.Sprintf(`"%v"`, t.str)
+	t.value = fmt.Sprintf("%c%v%c", quote, t.str, quote)
 	t.startPos = *pos
 	return t
 }
 
+// trySkipComment consumes a "// ..." or "/* ... */" comment starting at the
+// current position, reporting whether one was found. It is only called when
+// AllowComments is set, so a stray '/' in plain JSON still falls through to
+// the usual bad-token handling.
+func (j *Reader) trySkipComment() (bool, error) {
+	j.readChar()
+
+	c, ok := j.peekChar()
+
+	if !ok {
+		return false, fmt.Errorf("unexpected EOF after /")
+	}
+
+	switch c {
+	case '/':
+		j.readChar()
+
+		for {
+			c, ok := j.peekChar()
+
+			if !ok || c == '\n' {
+				break
+			}
+
+			j.readChar()
+		}
+
+		return true, nil
+	case '*':
+		j.readChar()
+
+		prevStar := false
+
+		for {
+			c, ok := j.peekChar()
+
+			if !ok {
+				return false, fmt.Errorf("unterminated /* comment")
+			}
+
+			j.readChar()
+
+			if prevStar && c == '/' {
+				return true, nil
+			}
+
+			prevStar = c == '*'
+		}
+	default:
+		return false, fmt.Errorf("unexpected character after /: %q", c)
+	}
+}
+
 func (j *Reader) newBasicToken(kind tokenKind, value string, pos *position) jsonToken {
 	t := &jsonBasicToken{
 		kind:     kind,
@@ -279,35 +411,127 @@ func (j *Reader) newBasicToken(kind tokenKind, value string, pos *position) json
 	return t
 }
 
-func (j *Reader) readNumberToken() jsonToken {
-	var b strings.Builder
-	isInt := true
+// consumeRune advances past c, which the caller has already peeked, and
+// appends it to b.
+func (j *Reader) consumeRune(b *strings.Builder, c rune) {
+	j.readChar()
+	j.col++
+	b.WriteRune(c)
+}
 
-	pos := j.getPos()
+// readDigits appends a run of ASCII digits to b, returning how many were read.
+func (j *Reader) readDigits(b *strings.Builder) int {
+	n := 0
 
 	for {
 		c, ok := j.peekChar()
 
-		if !ok {
+		if !ok || c < '0' || c > '9' {
 			break
 		}
 
-		if c == '.' && isInt {
-			isInt = false
-			fmt.Fprint(&b, c)
-		} else if c >= '0' && c <= '9' {
-			fmt.Fprint(&b, c)
-		} else {
+		j.consumeRune(b, c)
+		n++
+	}
+
+	return n
+}
+
+// readLetters appends a run of ASCII letters to b, for recognizing the
+// "Infinity" keyword after a leading '-'.
+func (j *Reader) readLetters(b *strings.Builder) {
+	for {
+		c, ok := j.peekChar()
+
+		if !ok || !((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
 			break
 		}
 
-		j.readChar()
-		j.col++
+		j.consumeRune(b, c)
+	}
+}
+
+// readNumberToken reads a JSON number, implementing the full RFC 8259
+// grammar: -? (0 | [1-9][0-9]*) (\.[0-9]+)? ([eE][+-]?[0-9]+)?. When the
+// relevant Extensions are set it also recognizes 0x-prefixed hex integers
+// and the NaN/Infinity/-Infinity literals.
+func (j *Reader) readNumberToken() jsonToken {
+	var b strings.Builder
+
+	pos := j.getPos()
+
+	if c, ok := j.peekChar(); ok && c == '-' {
+		j.consumeRune(&b, c)
+
+		if j.hasExtension(AllowNaNInf) {
+			if c, ok := j.peekChar(); ok && c == 'I' {
+				j.readLetters(&b)
+				value := b.String()
+
+				if value == "-Infinity" {
+					return j.newBasicToken(kindNumber, value, pos)
+				}
+
+				return j.newBasicToken(kindBadToken, value, pos)
+			}
+		}
+	}
+
+	if j.hasExtension(AllowHexNumbers) {
+		if c, ok := j.peekChar(); ok && c == '0' {
+			j.consumeRune(&b, c)
+
+			if c, ok := j.peekChar(); ok && (c == 'x' || c == 'X') {
+				j.consumeRune(&b, c)
+
+				for {
+					c, ok := j.peekChar()
+
+					if !ok || !isHexDigit(c) {
+						break
+					}
+
+					j.consumeRune(&b, c)
+				}
+
+				return j.newBasicToken(kindNumber, b.String(), pos)
+			}
+		}
+	}
+
+	j.readDigits(&b)
+
+	if digits := strings.TrimPrefix(b.String(), "-"); digits == "" || (len(digits) > 1 && digits[0] == '0') {
+		return j.newBasicToken(kindBadToken, b.String(), pos)
+	}
+
+	if c, ok := j.peekChar(); ok && c == '.' {
+		j.consumeRune(&b, c)
+
+		if j.readDigits(&b) == 0 {
+			return j.newBasicToken(kindBadToken, b.String(), pos)
+		}
+	}
+
+	if c, ok := j.peekChar(); ok && (c == 'e' || c == 'E') {
+		j.consumeRune(&b, c)
+
+		if c, ok := j.peekChar(); ok && (c == '+' || c == '-') {
+			j.consumeRune(&b, c)
+		}
+
+		if j.readDigits(&b) == 0 {
+			return j.newBasicToken(kindBadToken, b.String(), pos)
+		}
 	}
 
 	return j.newBasicToken(kindNumber, b.String(), pos)
 }
 
+func isHexDigit(c rune) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
 func (j *Reader) peekToken() jsonToken {
 	if j.peekedToken == nil {
 		j.peekedToken = j.nextToken()
@@ -316,6 +540,19 @@ func (j *Reader) peekToken() jsonToken {
 	return j.peekedToken
 }
 
+// peekSecondToken peeks one token past the next one, lexing it ahead of time
+// if needed. It only exists to let mayEatTrailingComma confirm that a comma
+// is followed by a closing brace/bracket before treating it as trailing.
+func (j *Reader) peekSecondToken() jsonToken {
+	j.peekToken()
+
+	if j.peekedToken2 == nil {
+		j.peekedToken2 = j.lexToken()
+	}
+
+	return j.peekedToken2
+}
+
 func (j *Reader) eat(kind tokenKind) (jsonToken, error) {
 	t := j.nextToken()
 
@@ -326,10 +563,34 @@ func (j *Reader) eat(kind tokenKind) (jsonToken, error) {
 	return t, fmt.Errorf("expect %v %v", kind.String(), t.Pos(nil).String())
 }
 
+// mayEat consumes the next token and reports true if it is of kind, leaving
+// the stream unadvanced (other than the peek already cached by peekToken)
+// when it isn't.
 func (j *Reader) mayEat(kind tokenKind) bool {
-	t := j.peekToken()
+	if j.peekToken().Kind() != kind {
+		return false
+	}
+
+	j.nextToken()
+
+	return true
+}
 
-	return t.Kind() == kind
+// mayEatTrailingComma consumes a "," immediately followed by closeKind, the
+// shape AllowTrailingCommas tolerates just before a closing "}" or "]". It is
+// a no-op unless AllowTrailingCommas is set.
+func (j *Reader) mayEatTrailingComma(closeKind tokenKind) bool {
+	if !j.hasExtension(AllowTrailingCommas) {
+		return false
+	}
+
+	if j.peekToken().Kind() != kindComma || j.peekSecondToken().Kind() != closeKind {
+		return false
+	}
+
+	j.nextToken()
+
+	return true
 }
 
 func (j *Reader) TryReadNull() bool {
@@ -343,12 +604,16 @@ func (j *Reader) ReadStartObject() error {
 }
 
 func (j *Reader) ReadEndObject() error {
+	j.mayEatTrailingComma(kindRightBrace)
+
 	_, err := j.eat(kindRightBrace)
 
 	return err
 }
 
 func (j *Reader) TryReadEndObject() bool {
+	j.mayEatTrailingComma(kindRightBrace)
+
 	return j.mayEat(kindRightBrace)
 }
 
@@ -359,12 +624,16 @@ func (j *Reader) ReadStartArray() error {
 }
 
 func (j *Reader) ReadEndArray() error {
+	j.mayEatTrailingComma(kindRightBracket)
+
 	_, err := j.eat(kindRightBracket)
 
 	return err
 }
 
 func (j *Reader) TryReadEndArray() bool {
+	j.mayEatTrailingComma(kindRightBracket)
+
 	return j.mayEat(kindRightBracket)
 }
 
@@ -407,7 +676,11 @@ func (j *Reader) ReadInt() (int, error) {
 		return 0, err
 	}
 
-	return strconv.Atoi(t.String())
+	// Base 0, like ReadInt64, so a 0x-prefixed token parses under
+	// AllowHexNumbers instead of only ReadInt64 honoring the extension.
+	v, err := strconv.ParseInt(t.String(), 0, strconv.IntSize)
+
+	return int(v), err
 }
 
 func (j *Reader) ReadInt64() (int64, error) {
@@ -432,7 +705,7 @@ func (j *Reader) ReadFloat() (float64, error) {
 
 func (j *Reader) ReadBool() (bool, error) {
 	if j.mayEat(kindTrue) {
-		return false, nil
+		return true, nil
 	} else if j.mayEat(kindFalse) {
 		return false, nil
 	}
@@ -445,3 +718,134 @@ func (j *Reader) ReadBool() (bool, error) {
 func (j *Reader) ReadEndField() {
 	j.mayEat(kindComma)
 }
+
+// Kind classifies the shape of the next JSON value in the stream, as reported
+// by PeekKind, so callers can decide how to read it without consuming it.
+type Kind int
+
+const (
+	KindEOF Kind = iota
+	KindString
+	KindNumber
+	KindBool
+	KindNull
+	KindObject
+	KindArray
+	KindInvalid
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindEOF:
+		return "EOF"
+	case KindString:
+		return "String"
+	case KindNumber:
+		return "Number"
+	case KindBool:
+		return "Bool"
+	case KindNull:
+		return "Null"
+	case KindObject:
+		return "Object"
+	case KindArray:
+		return "Array"
+	}
+
+	return "Invalid"
+}
+
+// PeekKind reports the kind of the next JSON value without consuming any
+// tokens, letting callers branch between e.g. ReadStartObject and ReadString.
+func (j *Reader) PeekKind() Kind {
+	switch j.peekToken().Kind() {
+	case kindEOF:
+		return KindEOF
+	case kindString:
+		return KindString
+	case kindNumber:
+		return KindNumber
+	case kindTrue, kindFalse:
+		return KindBool
+	case kindNull:
+		return KindNull
+	case kindLeftBrace:
+		return KindObject
+	case kindLeftBracket:
+		return KindArray
+	default:
+		return KindInvalid
+	}
+}
+
+// ReadFieldName reads the next object field's name and consumes the
+// following colon, without requiring the caller to know the name in advance.
+// Unlike ReadStartField, it does not validate the name against an expected value.
+func (j *Reader) ReadFieldName() (string, error) {
+	t, err := j.eat(kindString)
+
+	if err != nil {
+		return "", err
+	}
+
+	j.eat(kindColon)
+
+	return t.(*jsonStringToken).str, nil
+}
+
+// RawMessage holds the undecoded JSON text of a value, as reconstructed from
+// its tokens by ReadRawValue.
+type RawMessage []byte
+
+// SkipValue consumes exactly one JSON value (scalar, object or array),
+// tracking {}/[] nesting depth through nextToken, without decoding it. It is
+// meant for large documents where the caller only cares about a few fields
+// and wants to discard the rest cheaply.
+func (j *Reader) SkipValue() error {
+	return j.walkValue(nil)
+}
+
+// ReadRawValue captures the raw JSON text of the next value without
+// decoding its interior, so the caller can pass a subtree through unchanged
+// or defer parsing it. The text is reconstructed from the underlying
+// tokens, so it is always a compact (whitespace-free) re-serialization of
+// the original.
+func (j *Reader) ReadRawValue() (RawMessage, error) {
+	var buf strings.Builder
+
+	if err := j.walkValue(&buf); err != nil {
+		return nil, err
+	}
+
+	return RawMessage(buf.String()), nil
+}
+
+// walkValue consumes the next JSON value token by token. When buf is
+// non-nil, each consumed token's text is appended to it, reconstructing the
+// value's raw JSON representation as a side effect of the skip.
+func (j *Reader) walkValue(buf *strings.Builder) error {
+	depth := 0
+
+	for {
+		t := j.nextToken()
+
+		if buf != nil {
+			buf.WriteString(t.String())
+		}
+
+		switch t.Kind() {
+		case kindEOF:
+			return fmt.Errorf("unexpected EOF while skipping value %v", t.Pos(nil).String())
+		case kindBadToken:
+			return fmt.Errorf("bad token %v %v", t.String(), t.Pos(nil).String())
+		case kindLeftBrace, kindLeftBracket:
+			depth++
+		case kindRightBrace, kindRightBracket:
+			depth--
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
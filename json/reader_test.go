@@ -0,0 +1,151 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadInt(t *testing.T) {
+	r := NewReader(strings.NewReader("42"))
+
+	v, err := r.ReadInt()
+
+	if err != nil {
+		t.Fatalf("ReadInt: %v", err)
+	}
+
+	if v != 42 {
+		t.Fatalf("ReadInt: got %v, want 42", v)
+	}
+}
+
+func TestReadFloat(t *testing.T) {
+	cases := map[string]float64{
+		"100":    100,
+		"123":    123,
+		"-1.5":   -1.5,
+		"1e3":    1000,
+		"-2.5E2": -250,
+	}
+
+	for src, want := range cases {
+		r := NewReader(strings.NewReader(src))
+
+		v, err := r.ReadFloat()
+
+		if err != nil {
+			t.Fatalf("ReadFloat(%q): %v", src, err)
+		}
+
+		if v != want {
+			t.Fatalf("ReadFloat(%q): got %v, want %v", src, v, want)
+		}
+	}
+}
+
+func TestReadBool(t *testing.T) {
+	r := NewReader(strings.NewReader("true"))
+
+	v, err := r.ReadBool()
+
+	if err != nil {
+		t.Fatalf("ReadBool(true): %v", err)
+	}
+
+	if v != true {
+		t.Fatalf("ReadBool(true): got %v, want true", v)
+	}
+
+	r = NewReader(strings.NewReader("false"))
+
+	v, err = r.ReadBool()
+
+	if err != nil {
+		t.Fatalf("ReadBool(false): %v", err)
+	}
+
+	if v != false {
+		t.Fatalf("ReadBool(false): got %v, want false", v)
+	}
+}
+
+func TestReadBoolFollowedByEndObject(t *testing.T) {
+	r := NewReader(strings.NewReader(`{"b":"hello","a":true}`))
+
+	if err := r.ReadStartObject(); err != nil {
+		t.Fatalf("ReadStartObject: %v", err)
+	}
+
+	got := map[string]any{}
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			t.Fatalf("ReadFieldName: %v", err)
+		}
+
+		if name == "b" {
+			v, err := r.ReadString()
+
+			if err != nil {
+				t.Fatalf("ReadString: %v", err)
+			}
+
+			got[name] = v
+		} else {
+			v, err := r.ReadBool()
+
+			if err != nil {
+				t.Fatalf("ReadBool: %v", err)
+			}
+
+			got[name] = v
+		}
+
+		r.ReadEndField()
+	}
+
+	if got["b"] != "hello" || got["a"] != true {
+		t.Fatalf("ReadBoolFollowedByEndObject: got %v", got)
+	}
+}
+
+func TestReadObjectMultipleFields(t *testing.T) {
+	r := NewReader(strings.NewReader(`{"a":1,"b":2,"c":3}`))
+
+	if err := r.ReadStartObject(); err != nil {
+		t.Fatalf("ReadStartObject: %v", err)
+	}
+
+	got := map[string]int{}
+
+	for !r.TryReadEndObject() {
+		name, err := r.ReadFieldName()
+
+		if err != nil {
+			t.Fatalf("ReadFieldName: %v", err)
+		}
+
+		v, err := r.ReadInt()
+
+		if err != nil {
+			t.Fatalf("ReadInt: %v", err)
+		}
+
+		got[name] = v
+		r.ReadEndField()
+	}
+
+	want := map[string]int{"a": 1, "b": 2, "c": 3}
+
+	if len(got) != len(want) {
+		t.Fatalf("ReadObjectMultipleFields: got %v, want %v", got, want)
+	}
+
+	for k, v := range want {
+		if got[k] != v {
+			t.Fatalf("ReadObjectMultipleFields: got %v, want %v", got, want)
+		}
+	}
+}
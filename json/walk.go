@@ -0,0 +1,251 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Action tells Walk how to proceed after an EventHandler callback returns.
+type Action int
+
+const (
+	// Continue keeps walking normally.
+	Continue Action = iota
+	// SkipValue discards the value the callback was just told about
+	// (the object/array just started, or the field named by OnKey)
+	// without decoding it.
+	SkipValue
+	// Stop ends the walk immediately; Walk returns nil.
+	Stop
+)
+
+// PathSegment identifies one step of the breadcrumb Walk maintains while
+// traversing a document: either an object field (Key set, Index zero) or an
+// array element (Index set, Key empty).
+type PathSegment struct {
+	Key   string
+	Index int
+}
+
+// Position locates a point in the input stream.
+type Position struct {
+	Row    int
+	Col    int
+	Offset int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("[%v, %v]", p.Row, p.Col)
+}
+
+func newPosition(pos *position) Position {
+	return Position{Row: pos.row, Col: pos.col, Offset: pos.offset}
+}
+
+// EventHandler receives one callback per structural event as Walk drives a
+// Reader across a document, along with the current breadcrumb path and
+// stream Position. Each callback returns an Action telling Walk whether to
+// keep going, skip the value it was just told about, or stop the walk
+// entirely. This lets callers process documents far larger than memory
+// (e.g. NDJSON logs, map/reduce inputs) without building any intermediate
+// tree.
+type EventHandler interface {
+	OnObjectStart(path []PathSegment, pos Position) Action
+	OnObjectEnd(path []PathSegment, pos Position) Action
+	OnArrayStart(path []PathSegment, pos Position) Action
+	OnArrayEnd(path []PathSegment, pos Position) Action
+	OnKey(key string, path []PathSegment, pos Position) Action
+	OnString(value string, path []PathSegment, pos Position) Action
+	OnInt(value int64, path []PathSegment, pos Position) Action
+	OnFloat(value float64, path []PathSegment, pos Position) Action
+	OnBool(value bool, path []PathSegment, pos Position) Action
+	OnNull(path []PathSegment, pos Position) Action
+}
+
+// Walk drives j to the end of the stream, invoking handler for every
+// structural event. It uses SkipValue internally whenever handler asks to
+// skip a value, so skipped subtrees are never decoded.
+func (j *Reader) Walk(handler EventHandler) error {
+	for {
+		action, err := j.walkNode(handler, nil)
+
+		if err != nil || action == Stop {
+			return err
+		}
+
+		if j.PeekKind() == KindEOF {
+			return nil
+		}
+	}
+}
+
+func (j *Reader) tokenPos() Position {
+	return newPosition(j.peekToken().Pos(nil))
+}
+
+func pushPath(path []PathSegment, seg PathSegment) []PathSegment {
+	out := make([]PathSegment, len(path)+1)
+	copy(out, path)
+	out[len(path)] = seg
+
+	return out
+}
+
+func (j *Reader) walkNode(handler EventHandler, path []PathSegment) (Action, error) {
+	pos := j.tokenPos()
+
+	switch j.PeekKind() {
+	case KindObject:
+		return j.walkObject(handler, path, pos)
+	case KindArray:
+		return j.walkArray(handler, path, pos)
+	default:
+		return j.walkScalar(handler, path, pos)
+	}
+}
+
+func (j *Reader) walkObject(handler EventHandler, path []PathSegment, pos Position) (Action, error) {
+	if action := handler.OnObjectStart(path, pos); action != Continue {
+		if action == SkipValue {
+			return Continue, j.SkipValue()
+		}
+
+		return Stop, nil
+	}
+
+	if err := j.ReadStartObject(); err != nil {
+		return Stop, err
+	}
+
+	for {
+		endPos := j.tokenPos()
+
+		if j.TryReadEndObject() {
+			return handler.OnObjectEnd(path, endPos), nil
+		}
+
+		name, err := j.ReadFieldName()
+
+		if err != nil {
+			return Stop, err
+		}
+
+		switch action := handler.OnKey(name, path, j.tokenPos()); action {
+		case Stop:
+			return Stop, nil
+		case SkipValue:
+			if err := j.SkipValue(); err != nil {
+				return Stop, err
+			}
+		default:
+			childAction, err := j.walkNode(handler, pushPath(path, PathSegment{Key: name}))
+
+			if err != nil {
+				return Stop, err
+			}
+
+			if childAction == Stop {
+				return Stop, nil
+			}
+		}
+
+		j.ReadEndField()
+	}
+}
+
+func (j *Reader) walkArray(handler EventHandler, path []PathSegment, pos Position) (Action, error) {
+	if action := handler.OnArrayStart(path, pos); action != Continue {
+		if action == SkipValue {
+			return Continue, j.SkipValue()
+		}
+
+		return Stop, nil
+	}
+
+	if err := j.ReadStartArray(); err != nil {
+		return Stop, err
+	}
+
+	index := 0
+
+	for {
+		endPos := j.tokenPos()
+
+		if j.TryReadEndArray() {
+			return handler.OnArrayEnd(path, endPos), nil
+		}
+
+		childAction, err := j.walkNode(handler, pushPath(path, PathSegment{Index: index}))
+
+		if err != nil {
+			return Stop, err
+		}
+
+		if childAction == Stop {
+			return Stop, nil
+		}
+
+		j.ReadEndField()
+		index++
+	}
+}
+
+// walkScalar reads the next scalar value and reports it through the
+// matching callback. SkipValue has no extra effect here since the value is
+// already consumed by the time the callback fires; it is treated the same
+// as Continue.
+func (j *Reader) walkScalar(handler EventHandler, path []PathSegment, pos Position) (Action, error) {
+	switch j.PeekKind() {
+	case KindString:
+		v, err := j.ReadString()
+
+		if err != nil {
+			return Stop, err
+		}
+
+		return normalizeScalarAction(handler.OnString(v, path, pos)), nil
+	case KindNumber:
+		s := strings.TrimPrefix(j.peekToken().String(), "-")
+		isHex := strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X")
+
+		if !isHex && (strings.ContainsAny(s, ".eE") || s == "NaN" || s == "Infinity") {
+			v, err := j.ReadFloat()
+
+			if err != nil {
+				return Stop, err
+			}
+
+			return normalizeScalarAction(handler.OnFloat(v, path, pos)), nil
+		}
+
+		v, err := j.ReadInt64()
+
+		if err != nil {
+			return Stop, err
+		}
+
+		return normalizeScalarAction(handler.OnInt(v, path, pos)), nil
+	case KindBool:
+		v, err := j.ReadBool()
+
+		if err != nil {
+			return Stop, err
+		}
+
+		return normalizeScalarAction(handler.OnBool(v, path, pos)), nil
+	case KindNull:
+		j.TryReadNull()
+
+		return normalizeScalarAction(handler.OnNull(path, pos)), nil
+	default:
+		return Stop, fmt.Errorf("json: unexpected token in Walk %v", pos)
+	}
+}
+
+func normalizeScalarAction(action Action) Action {
+	if action == SkipValue {
+		return Continue
+	}
+
+	return action
+}
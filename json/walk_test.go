@@ -0,0 +1,78 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+type recordingHandler struct {
+	events []string
+}
+
+func (h *recordingHandler) OnObjectStart(path []PathSegment, pos Position) Action {
+	return Continue
+}
+
+func (h *recordingHandler) OnObjectEnd(path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "objectEnd")
+	return Continue
+}
+
+func (h *recordingHandler) OnArrayStart(path []PathSegment, pos Position) Action {
+	return Continue
+}
+
+func (h *recordingHandler) OnArrayEnd(path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "arrayEnd")
+	return Continue
+}
+
+func (h *recordingHandler) OnKey(key string, path []PathSegment, pos Position) Action {
+	return Continue
+}
+
+func (h *recordingHandler) OnString(value string, path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "string:"+value)
+	return Continue
+}
+
+func (h *recordingHandler) OnInt(value int64, path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "int")
+	return Continue
+}
+
+func (h *recordingHandler) OnFloat(value float64, path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "float")
+	return Continue
+}
+
+func (h *recordingHandler) OnBool(value bool, path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "bool")
+	return Continue
+}
+
+func (h *recordingHandler) OnNull(path []PathSegment, pos Position) Action {
+	h.events = append(h.events, "null")
+	return Continue
+}
+
+func TestWalkObjectEndingInBool(t *testing.T) {
+	r := NewReader(strings.NewReader(`{"ok":true}`))
+	h := &recordingHandler{}
+
+	if err := r.Walk(h); err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+
+	want := []string{"bool", "objectEnd"}
+
+	if len(h.events) != len(want) {
+		t.Fatalf("Walk: got %v, want %v", h.events, want)
+	}
+
+	for i := range want {
+		if h.events[i] != want[i] {
+			t.Fatalf("Walk: got %v, want %v", h.events, want)
+		}
+	}
+}
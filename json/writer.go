@@ -0,0 +1,197 @@
+package json
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Writer is the write-side counterpart of Reader: a low-level, streaming
+// token emitter that mirrors its method set (WriteStartObject/WriteString/
+// WriteInt/...) so the handwritten API stays symmetric in both directions.
+type Writer struct {
+	w     *bufio.Writer
+	stack []writerFrame
+}
+
+type writerFrame struct {
+	kind      tokenKind
+	wroteItem bool
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// beforeValue inserts the comma a value needs when it is itself an array
+// element. Object field values don't need this: WriteStartField already
+// placed the comma and colon ahead of them.
+func (w *Writer) beforeValue() {
+	if len(w.stack) == 0 {
+		return
+	}
+
+	top := &w.stack[len(w.stack)-1]
+
+	if top.kind == kindLeftBracket {
+		if top.wroteItem {
+			w.w.WriteByte(',')
+		}
+
+		top.wroteItem = true
+	}
+}
+
+func (w *Writer) WriteStartObject() error {
+	w.beforeValue()
+	w.stack = append(w.stack, writerFrame{kind: kindLeftBrace})
+
+	return w.w.WriteByte('{')
+}
+
+func (w *Writer) WriteEndObject() error {
+	if len(w.stack) == 0 || w.stack[len(w.stack)-1].kind != kindLeftBrace {
+		return fmt.Errorf("json: WriteEndObject without matching WriteStartObject")
+	}
+
+	w.stack = w.stack[:len(w.stack)-1]
+
+	return w.w.WriteByte('}')
+}
+
+func (w *Writer) WriteStartArray() error {
+	w.beforeValue()
+	w.stack = append(w.stack, writerFrame{kind: kindLeftBracket})
+
+	return w.w.WriteByte('[')
+}
+
+func (w *Writer) WriteEndArray() error {
+	if len(w.stack) == 0 || w.stack[len(w.stack)-1].kind != kindLeftBracket {
+		return fmt.Errorf("json: WriteEndArray without matching WriteStartArray")
+	}
+
+	w.stack = w.stack[:len(w.stack)-1]
+
+	return w.w.WriteByte(']')
+}
+
+// WriteStartField writes a field name and its colon. The value that follows
+// should be written with one of the Write* methods below.
+func (w *Writer) WriteStartField(name string) error {
+	if len(w.stack) == 0 || w.stack[len(w.stack)-1].kind != kindLeftBrace {
+		return fmt.Errorf("json: WriteStartField outside an object")
+	}
+
+	top := &w.stack[len(w.stack)-1]
+
+	if top.wroteItem {
+		w.w.WriteByte(',')
+	}
+
+	top.wroteItem = true
+
+	writeQuotedString(w.w, name)
+
+	return w.w.WriteByte(':')
+}
+
+func (w *Writer) WriteString(s string) error {
+	w.beforeValue()
+	writeQuotedString(w.w, s)
+
+	return nil
+}
+
+func (w *Writer) WriteInt(v int) error {
+	return w.WriteInt64(int64(v))
+}
+
+func (w *Writer) WriteInt64(v int64) error {
+	w.beforeValue()
+	_, err := w.w.WriteString(strconv.FormatInt(v, 10))
+
+	return err
+}
+
+func (w *Writer) WriteUint64(v uint64) error {
+	w.beforeValue()
+	_, err := w.w.WriteString(strconv.FormatUint(v, 10))
+
+	return err
+}
+
+func (w *Writer) WriteFloat(v float64) error {
+	w.beforeValue()
+	_, err := w.w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+
+	return err
+}
+
+func (w *Writer) WriteBool(v bool) error {
+	w.beforeValue()
+	_, err := w.w.WriteString(strconv.FormatBool(v))
+
+	return err
+}
+
+func (w *Writer) WriteNull() error {
+	w.beforeValue()
+	_, err := w.w.WriteString("null")
+
+	return err
+}
+
+// WriteRaw copies pre-encoded JSON bytes straight into the stream, e.g. the
+// output of a Marshaler.
+func (w *Writer) WriteRaw(raw []byte) error {
+	w.beforeValue()
+	_, err := w.w.Write(raw)
+
+	return err
+}
+
+// stringWriter is the subset of *bufio.Writer (also satisfied by
+// *bytes.Buffer) that writeQuotedString needs; the Writer calls it with its
+// own *bufio.Writer.
+type stringWriter interface {
+	WriteByte(byte) error
+	WriteString(string) (int, error)
+	WriteRune(rune) (int, error)
+}
+
+func writeQuotedString(w stringWriter, s string) {
+	w.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			w.WriteString(`\"`)
+		case '\\':
+			w.WriteString(`\\`)
+		case '\n':
+			w.WriteString(`\n`)
+		case '\r':
+			w.WriteString(`\r`)
+		case '\t':
+			w.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				w.WriteString(`\u`)
+				hex := strconv.FormatInt(int64(r), 16)
+				w.WriteString("0000"[len(hex):])
+				w.WriteString(hex)
+			} else {
+				w.WriteRune(r)
+			}
+		}
+	}
+
+	w.WriteByte('"')
+}
@@ -0,0 +1,40 @@
+package json
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriterObjectAndArray(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	w.WriteStartObject()
+	w.WriteStartField("name")
+	w.WriteString("ada")
+	w.WriteStartField("tags")
+	w.WriteStartArray()
+	w.WriteInt(1)
+	w.WriteBool(true)
+	w.WriteNull()
+	w.WriteEndArray()
+	w.WriteEndObject()
+	w.Flush()
+
+	want := `{"name":"ada","tags":[1,true,null]}`
+
+	if buf.String() != want {
+		t.Fatalf("Writer: got %s, want %s", buf.String(), want)
+	}
+}
+
+func TestWriterMismatchedEnd(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	w.WriteStartObject()
+
+	if err := w.WriteEndArray(); err == nil {
+		t.Fatalf("WriteEndArray: expected error for mismatched end, got nil")
+	}
+}